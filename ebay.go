@@ -1,13 +1,13 @@
 package ebay
 
 import (
+	"context"
 	"encoding/xml"
-	"errors"
+	"io/ioutil"
+	"net/http"
 	"net/url"
 	"strconv"
 	"time"
-
-	"github.com/heatxsink/go-httprequest"
 )
 
 const (
@@ -18,192 +18,366 @@ const (
 	GLOBAL_ID_EBAY_ES = "EBAY-ES"
 )
 
+const (
+	findingServiceURL = "http://svcs.ebay.com/services/search/FindingService/v1"
+	defaultUserAgent  = "go-ebay"
+)
+
 // Item for sale on EBay
 type Item struct {
-	ItemID        string    `xml:"itemId"`
-	Title         string    `xml:"title"`
-	Location      string    `xml:"location"`
-	CurrentPrice  float64   `xml:"sellingStatus>currentPrice"`
-	ShippingPrice float64   `xml:"shippingInfo>shippingServiceCost"`
-	BinPrice      float64   `xml:"listingInfo>buyItNowPrice"`
-	ShipsTo       []string  `xml:"shippingInfo>shipToLocations"`
-	ListingURL    string    `xml:"viewItemURL"`
-	ImageURL      string    `xml:"galleryURL"`
-	Site          string    `xml:"globalId"`
-	EndTime       time.Time `xml:"listingInfo>endTime"`
+	ItemID        string    `xml:"itemId" json:"itemId"`
+	Title         string    `xml:"title" json:"title"`
+	Location      string    `xml:"location" json:"location"`
+	CurrentPrice  float64   `xml:"sellingStatus>currentPrice" json:"currentPrice"`
+	ShippingPrice float64   `xml:"shippingInfo>shippingServiceCost" json:"shippingServiceCost"`
+	BinPrice      float64   `xml:"listingInfo>buyItNowPrice" json:"buyItNowPrice"`
+	ShipsTo       []string  `xml:"shippingInfo>shipToLocations" json:"shipToLocations"`
+	ListingURL    string    `xml:"viewItemURL" json:"viewItemURL"`
+	ImageURL      string    `xml:"galleryURL" json:"galleryURL"`
+	Site          string    `xml:"globalId" json:"globalId"`
+	EndTime       time.Time `xml:"listingInfo>endTime" json:"endTime"`
+}
+
+// PaginationOutput reports where a result page falls within the full result
+// set, as returned by the Finding API alongside every search response.
+type PaginationOutput struct {
+	PageNumber     int `xml:"pageNumber" json:"pageNumber"`
+	EntriesPerPage int `xml:"entriesPerPage" json:"entriesPerPage"`
+	TotalPages     int `xml:"totalPages" json:"totalPages"`
+	TotalEntries   int `xml:"totalEntries" json:"totalEntries"`
 }
 
 // FindItemsResponse from EBay
 type FindItemsResponse struct {
-	XMLName   xml.Name `xml:"findItemsByKeywordsResponse"`
-	Items     []Item   `xml:"searchResult>item"`
-	Timestamp string   `xml:"timestamp"`
+	XMLName    xml.Name         `xml:"findItemsByKeywordsResponse" json:"-"`
+	Items      []Item           `xml:"searchResult>item" json:"item"`
+	Pagination PaginationOutput `xml:"paginationOutput" json:"paginationOutput"`
+	Timestamp  string           `xml:"timestamp" json:"timestamp"`
 }
 
 // FindCompletedItemsResponse from EBay
 type FindCompletedItemsResponse struct {
-	XMLName   xml.Name `xml:"findCompletedItemsResponse"`
-	Items     []Item   `xml:"searchResult>item"`
-	Timestamp string   `xml:"timestamp"`
+	XMLName    xml.Name         `xml:"findCompletedItemsResponse" json:"-"`
+	Items      []Item           `xml:"searchResult>item" json:"item"`
+	Pagination PaginationOutput `xml:"paginationOutput" json:"paginationOutput"`
+	Timestamp  string           `xml:"timestamp" json:"timestamp"`
+}
+
+// FindItemsByCategoryResponse from EBay
+type FindItemsByCategoryResponse struct {
+	XMLName    xml.Name         `xml:"findItemsByCategoryResponse" json:"-"`
+	Items      []Item           `xml:"searchResult>item" json:"item"`
+	Pagination PaginationOutput `xml:"paginationOutput" json:"paginationOutput"`
+	Timestamp  string           `xml:"timestamp" json:"timestamp"`
+}
+
+// FindItemsAdvancedResponse from EBay
+type FindItemsAdvancedResponse struct {
+	XMLName    xml.Name         `xml:"findItemsAdvancedResponse" json:"-"`
+	Items      []Item           `xml:"searchResult>item" json:"item"`
+	Pagination PaginationOutput `xml:"paginationOutput" json:"paginationOutput"`
+	Timestamp  string           `xml:"timestamp" json:"timestamp"`
+}
+
+// FindItemsInEBayStoresResponse from EBay
+type FindItemsInEBayStoresResponse struct {
+	XMLName    xml.Name         `xml:"findItemsIneBayStoresResponse" json:"-"`
+	Items      []Item           `xml:"searchResult>item" json:"item"`
+	Pagination PaginationOutput `xml:"paginationOutput" json:"paginationOutput"`
+	Timestamp  string           `xml:"timestamp" json:"timestamp"`
+}
+
+// HistogramEntry is a single value/count pair within a histogram
+type HistogramEntry struct {
+	Value string `xml:"value" json:"value"`
+	Count int    `xml:"count" json:"count"`
+}
+
+// GetHistogramsResponse from EBay
+type GetHistogramsResponse struct {
+	XMLName            xml.Name         `xml:"getHistogramsResponse" json:"-"`
+	CategoryID         string           `xml:"categoryHistogramContainer>categoryId" json:"categoryId"`
+	ConditionHistogram []HistogramEntry `xml:"conditionHistogramContainer>conditionHistogram" json:"conditionHistogram"`
+	AspectHistogram    []HistogramEntry `xml:"aspectHistogramContainer>aspect>valueHistogram" json:"valueHistogram"`
+	Timestamp          string           `xml:"timestamp" json:"timestamp"`
 }
 
 // ErrorMessage from EBay
 type ErrorMessage struct {
-	XMLName xml.Name `xml:"errorMessage"`
-	Error   Error    `xml:"error"`
+	XMLName xml.Name `xml:"errorMessage" json:"-"`
+	Error   Error    `xml:"error" json:"error"`
 }
 
 // Error response from EBay
 type Error struct {
-	ErrorID   string `xml:"errorId"`
-	Domain    string `xml:"domain"`
-	Severity  string `xml:"severity"`
-	Category  string `xml:"category"`
-	Message   string `xml:"message"`
-	SubDomain string `xml:"subdomain"`
+	ErrorID   string `xml:"errorId" json:"errorId"`
+	Domain    string `xml:"domain" json:"domain"`
+	Severity  string `xml:"severity" json:"severity"`
+	Category  string `xml:"category" json:"category"`
+	Message   string `xml:"message" json:"message"`
+	SubDomain string `xml:"subdomain" json:"subdomain"`
 }
 
+// ResponseFormat selects the wire format the Finding API replies in.
+type ResponseFormat string
+
+const (
+	FormatXML  ResponseFormat = "XML"
+	FormatJSON ResponseFormat = "JSON"
+)
+
 // EBay API request
 type EBay struct {
-	ApplicationID string
-	HTTPRequest   *httprequest.HttpRequest
+	ApplicationID  string
+	UserAgent      string
+	ResponseFormat ResponseFormat
+	HTTPClient     *http.Client
 }
 
-type soldURL func(string, string, int) (string, error)
-type searchURL func(string, string, int, bool) (string, error)
+// NewFindingClient returns an EBay client that issues Finding API requests
+// through httpClient. If httpClient is nil, http.DefaultClient is used.
+func NewFindingClient(httpClient *http.Client, applicationID string) *EBay {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &EBay{
+		ApplicationID:  applicationID,
+		UserAgent:      defaultUserAgent,
+		ResponseFormat: FormatXML,
+		HTTPClient:     httpClient,
+	}
+}
+
+// format returns the effective response format, defaulting to FormatXML.
+func (e *EBay) format() ResponseFormat {
+	if e.ResponseFormat == "" {
+		return FormatXML
+	}
+	return e.ResponseFormat
+}
 
-// New EBay API request
-func New(applicationID string) *EBay {
-	e := EBay{}
-	e.ApplicationID = applicationID
-	e.HTTPRequest = httprequest.NewWithDefaults()
-	return &e
+// buildURL assembles a Finding API request URL for operationName, merging in
+// the caller-supplied params (keywords, pagination, itemFilters, ...) on top
+// of the parameters every operation requires.
+func (e *EBay) buildURL(operationName string, params url.Values) (string, error) {
+	u, err := url.Parse(findingServiceURL)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Add("OPERATION-NAME", operationName)
+	query.Add("SERVICE-VERSION", "1.0.0")
+	query.Add("SECURITY-APPNAME", e.ApplicationID)
+	query.Add("RESPONSE-DATA-FORMAT", string(e.format()))
+	query.Add("REST-PAYLOAD", "")
+	mergeValues(query, params)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
 }
 
-func (e *EBay) buildSoldURL(globalID string, keywords string, entriesPerPage int) (string, error) {
-	filters := url.Values{}
-	filters.Add("itemFilter(0).name", "Condition")
-	filters.Add("itemFilter(0).value(0)", "Used")
-	filters.Add("itemFilter(0).value(1)", "Unspecified")
-	filters.Add("itemFilter(1).name", "SoldItemsOnly")
-	filters.Add("itemFilter(1).value(0)", "true")
-	return e.buildURL(globalID, keywords, "findCompletedItems", entriesPerPage, filters)
+// do issues a GET against requestURL and returns the raw response body,
+// translating a non-200 status into an *APIError parsed from EBay's error
+// envelope in the negotiated response format. Cancellation and deadlines are
+// taken from ctx.
+func (e *EBay) do(ctx context.Context, requestURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	userAgent := e.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := e.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		apiErr, err := parseAPIError(body, e.format(), resp.StatusCode)
+		if err != nil {
+			return nil, err
+		}
+		return nil, apiErr
+	}
+	return body, nil
 }
 
-func (e *EBay) buildSearchURL(globalID string, keywords string, entriesPerPage int, binOnly bool) (string, error) {
-	filters := url.Values{}
-	filters.Add("itemFilter(0).name", "ListingType")
-	filters.Add("itemFilter(0).value(0)", "AuctionWithBIN")
+// FindItemsByKeywords returns items matching the keyword search terms.
+// extraFilters are merged with the ListingType filter derived from binOnly.
+func (e *EBay) FindItemsByKeywords(ctx context.Context, globalID string, keywords string, entriesPerPage int, binOnly bool, extraFilters ...ItemFilter) (FindItemsResponse, error) {
+	return e.findItemsByKeywordsPage(ctx, globalID, keywords, entriesPerPage, 1, binOnly, extraFilters...)
+}
 
+// findItemsByKeywordsPage is FindItemsByKeywords with an explicit
+// paginationInput.pageNumber, used by FindItemsByKeywordsAll to walk pages.
+func (e *EBay) findItemsByKeywordsPage(ctx context.Context, globalID string, keywords string, entriesPerPage int, pageNumber int, binOnly bool, extraFilters ...ItemFilter) (FindItemsResponse, error) {
+	var response FindItemsResponse
+	listingTypes := []string{"AuctionWithBIN"}
 	if !binOnly {
-		filters.Add("itemFilter(0).value(1)", "FixedPrice")
-		filters.Add("itemFilter(0).value(2)", "Auction")
+		listingTypes = append(listingTypes, "FixedPrice", "Auction")
 	}
-	return e.buildURL(globalID, keywords, "findItemsByKeywords", entriesPerPage, filters)
+	filters := append([]ItemFilter{FilterListingType(listingTypes...)}, extraFilters...)
+	filterParams, err := buildItemFilterParams(filters)
+	if err != nil {
+		return response, err
+	}
+	params := url.Values{}
+	params.Add("GLOBAL-ID", globalID)
+	params.Add("keywords", keywords)
+	params.Add("paginationInput.entriesPerPage", strconv.Itoa(entriesPerPage))
+	params.Add("paginationInput.pageNumber", strconv.Itoa(pageNumber))
+	mergeValues(params, filterParams)
+	requestURL, err := e.buildURL("findItemsByKeywords", params)
+	if err != nil {
+		return response, err
+	}
+	body, err := e.do(ctx, requestURL)
+	if err != nil {
+		return response, err
+	}
+	response.Items, response.Pagination, response.Timestamp, err = e.decodeFindResults(body, "findItemsByKeywordsResponse")
+	return response, err
 }
 
-func (e *EBay) buildURL(globalID string, keywords string, operationName string, entriesPerPage int, filters url.Values) (string, error) {
-	var u *url.URL
-	u, err := url.Parse("http://svcs.ebay.com/services/search/FindingService/v1")
+// FindSoldItems returns sold items by keyword. extraFilters are merged with
+// the Condition and SoldItemsOnly filters this operation always applies.
+func (e *EBay) FindSoldItems(ctx context.Context, globalID string, keywords string, entriesPerPage int, extraFilters ...ItemFilter) (FindCompletedItemsResponse, error) {
+	var response FindCompletedItemsResponse
+	filters := append([]ItemFilter{
+		FilterCondition("Used", "Unspecified"),
+		{Name: "SoldItemsOnly", Values: []string{"true"}},
+	}, extraFilters...)
+	filterParams, err := buildItemFilterParams(filters)
 	if err != nil {
-		return "", err
+		return response, err
 	}
 	params := url.Values{}
-	params.Add("OPERATION-NAME", operationName)
-	params.Add("SERVICE-VERSION", "1.0.0")
-	params.Add("SECURITY-APPNAME", e.ApplicationID)
 	params.Add("GLOBAL-ID", globalID)
-	params.Add("RESPONSE-DATA-FORMAT", "XML")
-	params.Add("REST-PAYLOAD", "")
 	params.Add("keywords", keywords)
 	params.Add("paginationInput.entriesPerPage", strconv.Itoa(entriesPerPage))
-	for key := range filters {
-		for _, val := range filters[key] {
-			params.Add(key, val)
-		}
+	mergeValues(params, filterParams)
+	requestURL, err := e.buildURL("findCompletedItems", params)
+	if err != nil {
+		return response, err
+	}
+	body, err := e.do(ctx, requestURL)
+	if err != nil {
+		return response, err
 	}
-	u.RawQuery = params.Encode()
-	return u.String(), err
+	response.Items, response.Pagination, response.Timestamp, err = e.decodeFindResults(body, "findCompletedItemsResponse")
+	return response, err
 }
 
-func (e *EBay) findItems(globalID string, keywords string, entriesPerPage int, url string) (FindItemsResponse, error) {
-	var response FindItemsResponse
-	headers := make(map[string]string)
-	headers["User-Agent"] = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_7_3) AppleWebKit/535.11 (KHTML, like Gecko) Chrome/17.0.963.56 Safari/535.11"
-	body, statusCode, err := e.HTTPRequest.Get(url, headers)
+// FindItemsByCategory returns items listed under the given category ID
+func (e *EBay) FindItemsByCategory(ctx context.Context, globalID string, categoryID string, entriesPerPage int, extraFilters ...ItemFilter) (FindItemsByCategoryResponse, error) {
+	var response FindItemsByCategoryResponse
+	filterParams, err := buildItemFilterParams(extraFilters)
 	if err != nil {
 		return response, err
 	}
-	if statusCode != 200 {
-		var em ErrorMessage
-		err = xml.Unmarshal([]byte(body), &em)
-		if err != nil {
-			return response, err
-		}
-		return response, errors.New(em.Error.Message)
+	params := url.Values{}
+	params.Add("GLOBAL-ID", globalID)
+	params.Add("categoryId", categoryID)
+	params.Add("paginationInput.entriesPerPage", strconv.Itoa(entriesPerPage))
+	mergeValues(params, filterParams)
+	requestURL, err := e.buildURL("findItemsByCategory", params)
+	if err != nil {
+		return response, err
 	}
-	err = xml.Unmarshal([]byte(body), &response)
+	body, err := e.do(ctx, requestURL)
 	if err != nil {
 		return response, err
 	}
-
+	response.Items, response.Pagination, response.Timestamp, err = e.decodeFindResults(body, "findItemsByCategoryResponse")
 	return response, err
 }
 
-// FindItemsByKeywords returns items matching the keyword search terms
-func (e *EBay) FindItemsByKeywords(globalID string, keywords string, entriesPerPage int, binOnly bool) (FindItemsResponse, error) {
-	var response FindItemsResponse
-	url, err := e.buildSearchURL(globalID, keywords, entriesPerPage, binOnly)
+// FindItemsAdvanced searches by keywords and/or category ID, combining both
+// when both are non-empty
+func (e *EBay) FindItemsAdvanced(ctx context.Context, globalID string, keywords string, categoryID string, entriesPerPage int, extraFilters ...ItemFilter) (FindItemsAdvancedResponse, error) {
+	var response FindItemsAdvancedResponse
+	filterParams, err := buildItemFilterParams(extraFilters)
 	if err != nil {
-		var response FindItemsResponse
 		return response, err
 	}
-	headers := make(map[string]string)
-	headers["User-Agent"] = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_7_3) AppleWebKit/535.11 (KHTML, like Gecko) Chrome/17.0.963.56 Safari/535.11"
-	body, statusCode, err := e.HTTPRequest.Get(url, headers)
+	params := url.Values{}
+	params.Add("GLOBAL-ID", globalID)
+	if keywords != "" {
+		params.Add("keywords", keywords)
+	}
+	if categoryID != "" {
+		params.Add("categoryId", categoryID)
+	}
+	params.Add("paginationInput.entriesPerPage", strconv.Itoa(entriesPerPage))
+	mergeValues(params, filterParams)
+	requestURL, err := e.buildURL("findItemsAdvanced", params)
 	if err != nil {
 		return response, err
 	}
-	if statusCode != 200 {
-		var em ErrorMessage
-		err = xml.Unmarshal([]byte(body), &em)
-		if err != nil {
-			return response, err
-		}
-		return response, errors.New(em.Error.Message)
-	}
-	err = xml.Unmarshal([]byte(body), &response)
+	body, err := e.do(ctx, requestURL)
 	if err != nil {
 		return response, err
 	}
+	response.Items, response.Pagination, response.Timestamp, err = e.decodeFindResults(body, "findItemsAdvancedResponse")
 	return response, err
 }
 
-// FindSoldItems returns sold items by keyword
-func (e *EBay) FindSoldItems(globalID string, keywords string, entriesPerPage int) (FindCompletedItemsResponse, error) {
-	var response FindCompletedItemsResponse
-	url, err := e.buildSoldURL(globalID, keywords, entriesPerPage)
+// FindItemsInEBayStores returns items matching the keyword search terms
+// from listings hosted in eBay Stores
+func (e *EBay) FindItemsInEBayStores(ctx context.Context, globalID string, keywords string, entriesPerPage int, extraFilters ...ItemFilter) (FindItemsInEBayStoresResponse, error) {
+	var response FindItemsInEBayStoresResponse
+	filterParams, err := buildItemFilterParams(extraFilters)
 	if err != nil {
 		return response, err
 	}
-	headers := make(map[string]string)
-	headers["User-Agent"] = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_7_3) AppleWebKit/535.11 (KHTML, like Gecko) Chrome/17.0.963.56 Safari/535.11"
-	body, statusCode, err := e.HTTPRequest.Get(url, headers)
+	params := url.Values{}
+	params.Add("GLOBAL-ID", globalID)
+	params.Add("keywords", keywords)
+	params.Add("paginationInput.entriesPerPage", strconv.Itoa(entriesPerPage))
+	mergeValues(params, filterParams)
+	requestURL, err := e.buildURL("findItemsIneBayStores", params)
 	if err != nil {
 		return response, err
 	}
-	if statusCode != 200 {
-		var em ErrorMessage
-		err = xml.Unmarshal([]byte(body), &em)
-		if err != nil {
-			return response, err
+	body, err := e.do(ctx, requestURL)
+	if err != nil {
+		return response, err
+	}
+	response.Items, response.Pagination, response.Timestamp, err = e.decodeFindResults(body, "findItemsIneBayStoresResponse")
+	return response, err
+}
+
+// mergeValues copies every key/value pair from src into dst.
+func mergeValues(dst url.Values, src url.Values) {
+	for key := range src {
+		for _, val := range src[key] {
+			dst.Add(key, val)
 		}
-		return response, errors.New(em.Error.Message)
 	}
-	err = xml.Unmarshal([]byte(body), &response)
+}
+
+// GetHistograms returns the category ID, condition histogram, and aspect
+// value histograms for categoryID, useful for building search facets. The
+// category histogram's per-category entries are not decoded.
+func (e *EBay) GetHistograms(ctx context.Context, globalID string, categoryID string) (GetHistogramsResponse, error) {
+	var response GetHistogramsResponse
+	params := url.Values{}
+	params.Add("GLOBAL-ID", globalID)
+	params.Add("categoryId", categoryID)
+	requestURL, err := e.buildURL("getHistograms", params)
+	if err != nil {
+		return response, err
+	}
+	body, err := e.do(ctx, requestURL)
 	if err != nil {
 		return response, err
 	}
-
+	err = e.decodeHistograms(body, &response)
 	return response, err
 }
@@ -1,6 +1,7 @@
 package ebay
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -11,10 +12,10 @@ var (
 
 func TestFindItemsByKeywords(t *testing.T) {
 	fmt.Println("ebay.FindItemsByKeywords")
-	e := New(test_application_id)
-	response, err := e.FindItemsByKeywords(GLOBAL_ID_EBAY_US, "DJM 900, DJM 850", 10, false)
+	e := NewFindingClient(nil, test_application_id)
+	response, err := e.FindItemsByKeywords(context.Background(), GLOBAL_ID_EBAY_US, "DJM 900, DJM 850", 10, false)
 	if err != nil {
-		t.Errorf("ERROR: ", err)
+		t.Errorf("ERROR: %v", err)
 	} else {
 		fmt.Println("Timestamp: ", response.Timestamp)
 		fmt.Println("Items:")
@@ -22,7 +23,7 @@ func TestFindItemsByKeywords(t *testing.T) {
 		for _, i := range response.Items {
 			fmt.Println("Title: ", i.Title)
 			fmt.Println("------")
-			fmt.Println("\tListing Url:     ", i.ListingUrl)
+			fmt.Println("\tListing Url:     ", i.ListingURL)
 			fmt.Println("\tBin Price:       ", i.BinPrice)
 			fmt.Println("\tCurrent Price:   ", i.CurrentPrice)
 			fmt.Println("\tShipping Price:  ", i.ShippingPrice)
@@ -32,3 +33,19 @@ func TestFindItemsByKeywords(t *testing.T) {
 		}
 	}
 }
+
+func TestFindItemsByCategory(t *testing.T) {
+	fmt.Println("ebay.FindItemsByCategory")
+	e := NewFindingClient(nil, test_application_id)
+	response, err := e.FindItemsByCategory(context.Background(), GLOBAL_ID_EBAY_US, "176984", 10)
+	if err != nil {
+		t.Errorf("ERROR: %v", err)
+	} else {
+		fmt.Println("Timestamp: ", response.Timestamp)
+		fmt.Println("Items:")
+		fmt.Println("------")
+		for _, i := range response.Items {
+			fmt.Println("Title: ", i.Title)
+		}
+	}
+}
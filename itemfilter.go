@@ -0,0 +1,194 @@
+package ebay
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Typed validation errors returned by buildItemFilterParams, distinguishing
+// a malformed filter set from an HTTP or API failure.
+var (
+	ErrInvalidDateTime               = errors.New("ebay: filter date must be RFC3339")
+	ErrInvalidPriceRange             = errors.New("ebay: filter price must be numeric")
+	ErrSellerExclusionConflict       = errors.New("ebay: Seller and ExcludeSeller cannot both be set")
+	ErrInvalidFeedbackRange          = errors.New("ebay: FeedbackScoreMax must be >= FeedbackScoreMin")
+	ErrMaxDistanceRequiresPostalCode = errors.New("ebay: MaxDistance requires a buyerPostalCode param")
+)
+
+// ItemFilter is a single Finding API itemFilter. Values holds the filter's
+// value(s); ParamName/ParamValue hold the optional paramName/paramValue pair
+// some filters require (e.g. Currency for MinPrice/MaxPrice, buyerPostalCode
+// for MaxDistance).
+type ItemFilter struct {
+	Name       string
+	Values     []string
+	ParamName  string
+	ParamValue string
+}
+
+// FilterCondition restricts results to the given item conditions (e.g. "New", "Used").
+func FilterCondition(conditions ...string) ItemFilter {
+	return ItemFilter{Name: "Condition", Values: conditions}
+}
+
+// FilterListingType restricts results to the given listing types (e.g. "Auction", "FixedPrice").
+func FilterListingType(listingTypes ...string) ItemFilter {
+	return ItemFilter{Name: "ListingType", Values: listingTypes}
+}
+
+// FilterMinPrice restricts results to items at or above amount, in currency.
+func FilterMinPrice(amount float64, currency string) ItemFilter {
+	return ItemFilter{
+		Name:       "MinPrice",
+		Values:     []string{strconv.FormatFloat(amount, 'f', -1, 64)},
+		ParamName:  "Currency",
+		ParamValue: currency,
+	}
+}
+
+// FilterMaxPrice restricts results to items at or below amount, in currency.
+func FilterMaxPrice(amount float64, currency string) ItemFilter {
+	return ItemFilter{
+		Name:       "MaxPrice",
+		Values:     []string{strconv.FormatFloat(amount, 'f', -1, 64)},
+		ParamName:  "Currency",
+		ParamValue: currency,
+	}
+}
+
+// FilterLocatedIn restricts results to items located in the given country code.
+func FilterLocatedIn(countryCode string) ItemFilter {
+	return ItemFilter{Name: "LocatedIn", Values: []string{countryCode}}
+}
+
+// FilterMaxDistance restricts results to items within distance miles of buyerPostalCode.
+func FilterMaxDistance(distance int, buyerPostalCode string) ItemFilter {
+	return ItemFilter{
+		Name:       "MaxDistance",
+		Values:     []string{strconv.Itoa(distance)},
+		ParamName:  "buyerPostalCode",
+		ParamValue: buyerPostalCode,
+	}
+}
+
+// FilterSeller restricts results to listings from the given sellers.
+func FilterSeller(sellers ...string) ItemFilter {
+	return ItemFilter{Name: "Seller", Values: sellers}
+}
+
+// FilterExcludeSeller excludes listings from the given sellers.
+func FilterExcludeSeller(sellers ...string) ItemFilter {
+	return ItemFilter{Name: "ExcludeSeller", Values: sellers}
+}
+
+// FilterFeedbackScoreMin restricts results to sellers with at least min feedback.
+func FilterFeedbackScoreMin(min int) ItemFilter {
+	return ItemFilter{Name: "FeedbackScoreMin", Values: []string{strconv.Itoa(min)}}
+}
+
+// FilterFeedbackScoreMax restricts results to sellers with at most max feedback.
+func FilterFeedbackScoreMax(max int) ItemFilter {
+	return ItemFilter{Name: "FeedbackScoreMax", Values: []string{strconv.Itoa(max)}}
+}
+
+// FilterEndTimeFrom restricts results to listings ending at or after t.
+func FilterEndTimeFrom(t time.Time) ItemFilter {
+	return ItemFilter{Name: "EndTimeFrom", Values: []string{t.UTC().Format(time.RFC3339)}}
+}
+
+// FilterEndTimeTo restricts results to listings ending at or before t.
+func FilterEndTimeTo(t time.Time) ItemFilter {
+	return ItemFilter{Name: "EndTimeTo", Values: []string{t.UTC().Format(time.RFC3339)}}
+}
+
+// FilterExcludeCategory excludes the given category IDs from the results.
+func FilterExcludeCategory(categoryIDs ...string) ItemFilter {
+	return ItemFilter{Name: "ExcludeCategory", Values: categoryIDs}
+}
+
+// FilterPaymentMethod restricts results to listings accepting the given payment method.
+func FilterPaymentMethod(method string) ItemFilter {
+	return ItemFilter{Name: "PaymentMethod", Values: []string{method}}
+}
+
+// buildItemFilterParams validates filters for the cross-filter invariants the
+// Finding API imposes and emits the numbered itemFilter(N).name/.value(M)/
+// .paramName/.paramValue query parameters.
+func buildItemFilterParams(filters []ItemFilter) (url.Values, error) {
+	if err := validateItemFilters(filters); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	for n, f := range filters {
+		prefix := "itemFilter(" + strconv.Itoa(n) + ")"
+		params.Add(prefix+".name", f.Name)
+		for m, v := range f.Values {
+			params.Add(prefix+".value("+strconv.Itoa(m)+")", v)
+		}
+		if f.ParamName != "" {
+			params.Add(prefix+".paramName", f.ParamName)
+			params.Add(prefix+".paramValue", f.ParamValue)
+		}
+	}
+	return params, nil
+}
+
+func validateItemFilters(filters []ItemFilter) error {
+	var hasSeller, hasExcludeSeller bool
+	var feedbackMin, feedbackMax int
+	var hasFeedbackMin, hasFeedbackMax bool
+
+	for _, f := range filters {
+		switch f.Name {
+		case "Seller":
+			hasSeller = len(f.Values) > 0
+		case "ExcludeSeller":
+			hasExcludeSeller = len(f.Values) > 0
+		case "FeedbackScoreMin":
+			v, err := filterInt(f)
+			if err != nil {
+				return err
+			}
+			feedbackMin, hasFeedbackMin = v, true
+		case "FeedbackScoreMax":
+			v, err := filterInt(f)
+			if err != nil {
+				return err
+			}
+			feedbackMax, hasFeedbackMax = v, true
+		case "MaxDistance":
+			if f.ParamName != "buyerPostalCode" || f.ParamValue == "" {
+				return ErrMaxDistanceRequiresPostalCode
+			}
+		case "MinPrice", "MaxPrice":
+			for _, v := range f.Values {
+				if _, err := strconv.ParseFloat(v, 64); err != nil {
+					return ErrInvalidPriceRange
+				}
+			}
+		case "EndTimeFrom", "EndTimeTo":
+			for _, v := range f.Values {
+				if _, err := time.Parse(time.RFC3339, v); err != nil {
+					return ErrInvalidDateTime
+				}
+			}
+		}
+	}
+
+	if hasSeller && hasExcludeSeller {
+		return ErrSellerExclusionConflict
+	}
+	if hasFeedbackMin && hasFeedbackMax && feedbackMax < feedbackMin {
+		return ErrInvalidFeedbackRange
+	}
+	return nil
+}
+
+func filterInt(f ItemFilter) (int, error) {
+	if len(f.Values) == 0 {
+		return 0, nil
+	}
+	return strconv.Atoi(f.Values[0])
+}
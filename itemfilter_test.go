@@ -0,0 +1,60 @@
+package ebay
+
+import "testing"
+
+func TestBuildItemFilterParamsSellerExclusion(t *testing.T) {
+	_, err := buildItemFilterParams([]ItemFilter{
+		FilterSeller("seller-a"),
+		FilterExcludeSeller("seller-b"),
+	})
+	if err != ErrSellerExclusionConflict {
+		t.Errorf("expected ErrSellerExclusionConflict, got %v", err)
+	}
+}
+
+func TestBuildItemFilterParamsFeedbackRange(t *testing.T) {
+	_, err := buildItemFilterParams([]ItemFilter{
+		FilterFeedbackScoreMin(100),
+		FilterFeedbackScoreMax(50),
+	})
+	if err != ErrInvalidFeedbackRange {
+		t.Errorf("expected ErrInvalidFeedbackRange, got %v", err)
+	}
+}
+
+func TestBuildItemFilterParamsMaxDistanceRequiresPostalCode(t *testing.T) {
+	_, err := buildItemFilterParams([]ItemFilter{
+		{Name: "MaxDistance", Values: []string{"50"}},
+	})
+	if err != ErrMaxDistanceRequiresPostalCode {
+		t.Errorf("expected ErrMaxDistanceRequiresPostalCode, got %v", err)
+	}
+}
+
+func TestBuildItemFilterParamsInvalidDateTime(t *testing.T) {
+	_, err := buildItemFilterParams([]ItemFilter{
+		{Name: "EndTimeFrom", Values: []string{"not-a-date"}},
+	})
+	if err != ErrInvalidDateTime {
+		t.Errorf("expected ErrInvalidDateTime, got %v", err)
+	}
+}
+
+func TestBuildItemFilterParamsEmitsNumberedParams(t *testing.T) {
+	params, err := buildItemFilterParams([]ItemFilter{
+		FilterListingType("Auction", "FixedPrice"),
+		FilterMinPrice(10, "USD"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Get("itemFilter(0).name") != "ListingType" {
+		t.Errorf("expected itemFilter(0).name to be ListingType, got %q", params.Get("itemFilter(0).name"))
+	}
+	if params.Get("itemFilter(0).value(1)") != "FixedPrice" {
+		t.Errorf("expected itemFilter(0).value(1) to be FixedPrice, got %q", params.Get("itemFilter(0).value(1)"))
+	}
+	if params.Get("itemFilter(1).paramValue") != "USD" {
+		t.Errorf("expected itemFilter(1).paramValue to be USD, got %q", params.Get("itemFilter(1).paramValue"))
+	}
+}
@@ -0,0 +1,263 @@
+package ebay
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// APIError is returned when the Finding API responds with a non-200 status.
+// It carries the fields from EBay's error envelope so callers can
+// programmatically distinguish failure modes (e.g. an invalid app ID from a
+// rate limit) instead of matching on the error message string.
+type APIError struct {
+	ErrorID    string
+	Domain     string
+	Severity   string
+	Category   string
+	SubDomain  string
+	Message    string
+	StatusCode int
+}
+
+func (a *APIError) Error() string {
+	return fmt.Sprintf("ebay: %s (errorId=%s domain=%s category=%s status=%d)", a.Message, a.ErrorID, a.Domain, a.Category, a.StatusCode)
+}
+
+// parseAPIError decodes body, in the given format, into an *APIError.
+func parseAPIError(body []byte, format ResponseFormat, statusCode int) (*APIError, error) {
+	if format == FormatJSON {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, err
+		}
+		envelope := jsonObjectAt(raw, "errorMessage")
+		errObj := jsonObjectAt(envelope, "error")
+		return &APIError{
+			ErrorID:    jsonFirstString(errObj, "errorId"),
+			Domain:     jsonFirstString(errObj, "domain"),
+			Severity:   jsonFirstString(errObj, "severity"),
+			Category:   jsonFirstString(errObj, "category"),
+			SubDomain:  jsonFirstString(errObj, "subdomain"),
+			Message:    jsonFirstString(errObj, "message"),
+			StatusCode: statusCode,
+		}, nil
+	}
+	var em ErrorMessage
+	if err := xml.Unmarshal(body, &em); err != nil {
+		return nil, err
+	}
+	return &APIError{
+		ErrorID:    em.Error.ErrorID,
+		Domain:     em.Error.Domain,
+		Severity:   em.Error.Severity,
+		Category:   em.Error.Category,
+		SubDomain:  em.Error.SubDomain,
+		Message:    em.Error.Message,
+		StatusCode: statusCode,
+	}, nil
+}
+
+// decodeFindResults parses a find*Response body in the client's negotiated
+// format, returning the items, pagination, and timestamp common to every
+// find operation. rootKey is the operation's JSON response root (ignored in
+// XML, since encoding/xml matches structurally regardless of root element).
+func (e *EBay) decodeFindResults(body []byte, rootKey string) ([]Item, PaginationOutput, string, error) {
+	if e.format() == FormatJSON {
+		return decodeFindResultsJSON(body, rootKey)
+	}
+	var parsed struct {
+		Items      []Item           `xml:"searchResult>item"`
+		Pagination PaginationOutput `xml:"paginationOutput"`
+		Timestamp  string           `xml:"timestamp"`
+	}
+	if err := xml.Unmarshal(body, &parsed); err != nil {
+		return nil, PaginationOutput{}, "", err
+	}
+	return parsed.Items, parsed.Pagination, parsed.Timestamp, nil
+}
+
+// decodeFindResultsJSON parses a find*Response body in the Finding API's
+// JSON format. Unlike XML, every JSON field (even scalars) is wrapped in a
+// one-element array, so the response can't be decoded with plain
+// encoding/json struct tags; instead each known field is picked out of the
+// generic map by name.
+func decodeFindResultsJSON(body []byte, rootKey string) ([]Item, PaginationOutput, string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, PaginationOutput{}, "", err
+	}
+	root := jsonObjectAt(raw, rootKey)
+	if root == nil {
+		return nil, PaginationOutput{}, "", fmt.Errorf("ebay: missing %q in JSON response", rootKey)
+	}
+	var items []Item
+	if searchResult := jsonObjectAt(root, "searchResult"); searchResult != nil {
+		for _, v := range jsonList(searchResult, "item") {
+			if obj, ok := v.(map[string]interface{}); ok {
+				items = append(items, itemFromJSON(obj))
+			}
+		}
+	}
+	var pagination PaginationOutput
+	if po := jsonObjectAt(root, "paginationOutput"); po != nil {
+		pagination = PaginationOutput{
+			PageNumber:     jsonFirstInt(po, "pageNumber"),
+			EntriesPerPage: jsonFirstInt(po, "entriesPerPage"),
+			TotalPages:     jsonFirstInt(po, "totalPages"),
+			TotalEntries:   jsonFirstInt(po, "totalEntries"),
+		}
+	}
+	return items, pagination, jsonFirstString(root, "timestamp"), nil
+}
+
+// decodeHistograms parses a getHistogramsResponse body in the client's
+// negotiated format into response.
+func (e *EBay) decodeHistograms(body []byte, response *GetHistogramsResponse) error {
+	if e.format() != FormatJSON {
+		return xml.Unmarshal(body, response)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+	root := jsonObjectAt(raw, "getHistogramsResponse")
+	if root == nil {
+		return fmt.Errorf("ebay: missing %q in JSON response", "getHistogramsResponse")
+	}
+	if categoryContainer := jsonObjectAt(root, "categoryHistogramContainer"); categoryContainer != nil {
+		response.CategoryID = jsonFirstString(categoryContainer, "categoryId")
+	}
+	if conditionContainer := jsonObjectAt(root, "conditionHistogramContainer"); conditionContainer != nil {
+		for _, v := range jsonList(conditionContainer, "conditionHistogram") {
+			if obj, ok := v.(map[string]interface{}); ok {
+				response.ConditionHistogram = append(response.ConditionHistogram, histogramEntryFromJSON(obj))
+			}
+		}
+	}
+	if aspectContainer := jsonObjectAt(root, "aspectHistogramContainer"); aspectContainer != nil {
+		for _, aspect := range jsonList(aspectContainer, "aspect") {
+			aspectObj, ok := aspect.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, v := range jsonList(aspectObj, "valueHistogram") {
+				if obj, ok := v.(map[string]interface{}); ok {
+					response.AspectHistogram = append(response.AspectHistogram, histogramEntryFromJSON(obj))
+				}
+			}
+		}
+	}
+	response.Timestamp = jsonFirstString(root, "timestamp")
+	return nil
+}
+
+func histogramEntryFromJSON(m map[string]interface{}) HistogramEntry {
+	return HistogramEntry{
+		Value: jsonFirstString(m, "value"),
+		Count: jsonFirstInt(m, "count"),
+	}
+}
+
+func itemFromJSON(m map[string]interface{}) Item {
+	item := Item{
+		ItemID:     jsonFirstString(m, "itemId"),
+		Title:      jsonFirstString(m, "title"),
+		Location:   jsonFirstString(m, "location"),
+		ListingURL: jsonFirstString(m, "viewItemURL"),
+		ImageURL:   jsonFirstString(m, "galleryURL"),
+		Site:       jsonFirstString(m, "globalId"),
+	}
+	if sellingStatus := jsonObjectAt(m, "sellingStatus"); sellingStatus != nil {
+		item.CurrentPrice = jsonMoneyValue(sellingStatus, "currentPrice")
+	}
+	if shippingInfo := jsonObjectAt(m, "shippingInfo"); shippingInfo != nil {
+		item.ShippingPrice = jsonMoneyValue(shippingInfo, "shippingServiceCost")
+		for _, v := range jsonList(shippingInfo, "shipToLocations") {
+			if s, ok := v.(string); ok {
+				item.ShipsTo = append(item.ShipsTo, s)
+			}
+		}
+	}
+	if listingInfo := jsonObjectAt(m, "listingInfo"); listingInfo != nil {
+		item.BinPrice = jsonMoneyValue(listingInfo, "buyItNowPrice")
+		if endTime := jsonFirstString(listingInfo, "endTime"); endTime != "" {
+			if t, err := time.Parse(time.RFC3339, endTime); err == nil {
+				item.EndTime = t
+			}
+		}
+	}
+	return item
+}
+
+// jsonMoneyValue reads a Finding API money field, which the JSON format
+// represents as {"@currencyId": "...", "__value__": "10.00"}.
+func jsonMoneyValue(m map[string]interface{}, key string) float64 {
+	switch v := jsonFirst(m, key).(type) {
+	case map[string]interface{}:
+		if s, ok := v["__value__"].(string); ok {
+			f, _ := strconv.ParseFloat(s, 64)
+			return f
+		}
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// jsonFirst returns the first element of the one-element array EBay's JSON
+// format wraps every field in, or the value itself if it isn't an array.
+func jsonFirst(m map[string]interface{}, key string) interface{} {
+	if m == nil {
+		return nil
+	}
+	v, ok := m[key]
+	if !ok {
+		return nil
+	}
+	if arr, ok := v.([]interface{}); ok {
+		if len(arr) == 0 {
+			return nil
+		}
+		return arr[0]
+	}
+	return v
+}
+
+func jsonFirstString(m map[string]interface{}, key string) string {
+	s, _ := jsonFirst(m, key).(string)
+	return s
+}
+
+func jsonFirstInt(m map[string]interface{}, key string) int {
+	switch v := jsonFirst(m, key).(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// jsonList returns key's value as a slice, treating a missing or non-array
+// value as empty. Unlike jsonFirst, it is used for fields that are
+// genuinely multi-valued (e.g. searchResult.item).
+func jsonList(m map[string]interface{}, key string) []interface{} {
+	if m == nil {
+		return nil
+	}
+	arr, _ := m[key].([]interface{})
+	return arr
+}
+
+// jsonObjectAt returns the object wrapped in key's one-element array.
+func jsonObjectAt(m map[string]interface{}, key string) map[string]interface{} {
+	obj, _ := jsonFirst(m, key).(map[string]interface{})
+	return obj
+}
@@ -0,0 +1,91 @@
+package ebay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFindItemsByKeywordsJSONFormat(t *testing.T) {
+	const body = `{
+		"findItemsByKeywordsResponse": [{
+			"ack": ["Success"],
+			"searchResult": [{
+				"item": [{
+					"itemId": ["123"],
+					"title": ["Turntable"],
+					"globalId": ["EBAY-US"],
+					"sellingStatus": [{"currentPrice": [{"@currencyId": "USD", "__value__": "199.99"}]}]
+				}]
+			}],
+			"paginationOutput": [{"pageNumber": ["1"], "totalPages": ["1"], "totalEntries": ["1"]}],
+			"timestamp": ["2026-07-26T00:00:00.000Z"]
+		}]
+	}`
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("RESPONSE-DATA-FORMAT") != "JSON" {
+				t.Errorf("expected RESPONSE-DATA-FORMAT=JSON, got %q", req.URL.Query().Get("RESPONSE-DATA-FORMAT"))
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+	e := NewFindingClient(client, "test-app-id")
+	e.ResponseFormat = FormatJSON
+
+	response, err := e.FindItemsByKeywords(context.Background(), GLOBAL_ID_EBAY_US, "turntable", 10, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Items) != 1 || response.Items[0].Title != "Turntable" {
+		t.Fatalf("expected one Turntable item, got %+v", response.Items)
+	}
+	if response.Items[0].CurrentPrice != 199.99 {
+		t.Errorf("expected CurrentPrice 199.99, got %v", response.Items[0].CurrentPrice)
+	}
+	if response.Pagination.TotalEntries != 1 {
+		t.Errorf("expected TotalEntries 1, got %d", response.Pagination.TotalEntries)
+	}
+}
+
+func TestFindItemsByKeywordsJSONAPIError(t *testing.T) {
+	const body = `{
+		"errorMessage": [{
+			"error": [{
+				"errorId": ["1001"],
+				"domain": ["Finding"],
+				"severity": ["Error"],
+				"category": ["Request"],
+				"message": ["Invalid application ID"],
+				"subdomain": ["Security"]
+			}]
+		}]
+	}`
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 500,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+	e := NewFindingClient(client, "test-app-id")
+	e.ResponseFormat = FormatJSON
+
+	_, err := e.FindItemsByKeywords(context.Background(), GLOBAL_ID_EBAY_US, "turntable", 10, false)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v (%T)", err, err)
+	}
+	if apiErr.ErrorID != "1001" || apiErr.StatusCode != 500 {
+		t.Errorf("unexpected APIError: %+v", apiErr)
+	}
+}
@@ -0,0 +1,68 @@
+package ebay
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter throttles successive page requests, e.g. to stay under eBay's
+// 5,000-calls-per-day-per-application-ID ceiling. Wait should block until a
+// call is permitted or ctx is done, and return ctx.Err() in the latter case.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// tokenBucket is a simple RateLimiter that permits one call every interval.
+type tokenBucket struct {
+	interval time.Duration
+	ticker   *time.Ticker
+}
+
+// NewTokenBucketLimiter returns a RateLimiter that permits one call per
+// interval, blocking callers in between. A zero interval disables throttling.
+func NewTokenBucketLimiter(interval time.Duration) RateLimiter {
+	if interval <= 0 {
+		return nil
+	}
+	return &tokenBucket{interval: interval, ticker: time.NewTicker(interval)}
+}
+
+func (t *tokenBucket) Wait(ctx context.Context) error {
+	select {
+	case <-t.ticker.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FindItemsByKeywordsAll walks every page of a keyword search, invoking
+// onItem for each item as its page arrives rather than buffering the whole
+// result set. It stops after paginationOutput.totalPages, after maxPages
+// pages (a maxPages of 0 means unlimited), when ctx is done, when limiter
+// (optional, may be nil) returns an error, or when onItem returns an error.
+func (e *EBay) FindItemsByKeywordsAll(ctx context.Context, globalID string, keywords string, entriesPerPage int, binOnly bool, maxPages int, limiter RateLimiter, onItem func(Item) error, extraFilters ...ItemFilter) error {
+	for page := 1; maxPages == 0 || page <= maxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		response, err := e.findItemsByKeywordsPage(ctx, globalID, keywords, entriesPerPage, page, binOnly, extraFilters...)
+		if err != nil {
+			return err
+		}
+		for _, item := range response.Items {
+			if err := onItem(item); err != nil {
+				return err
+			}
+		}
+		if page >= response.Pagination.TotalPages {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package ebay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func pageResponseBody(pageNumber, totalPages int) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<findItemsByKeywordsResponse>
+	<searchResult>
+		<item><itemId>item-%[1]d</itemId></item>
+	</searchResult>
+	<paginationOutput>
+		<pageNumber>%[1]d</pageNumber>
+		<totalPages>%[2]d</totalPages>
+	</paginationOutput>
+</findItemsByKeywordsResponse>`, pageNumber, totalPages)
+}
+
+func TestFindItemsByKeywordsAllWalksEveryPage(t *testing.T) {
+	const totalPages = 3
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			page := 1
+			if p := req.URL.Query().Get("paginationInput.pageNumber"); p == "2" || p == "3" {
+				page = int(p[0] - '0')
+			}
+			body := pageResponseBody(page, totalPages)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+	e := NewFindingClient(client, "test-app-id")
+
+	var seen []string
+	err := e.FindItemsByKeywordsAll(context.Background(), GLOBAL_ID_EBAY_US, "widget", 10, false, 0, nil, func(item Item) error {
+		seen = append(seen, item.ItemID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != totalPages {
+		t.Fatalf("expected %d items, got %d: %v", totalPages, len(seen), seen)
+	}
+}
+
+func TestFindItemsByKeywordsAllRespectsMaxPages(t *testing.T) {
+	const totalPages = 5
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			page := 1
+			if p := req.URL.Query().Get("paginationInput.pageNumber"); len(p) > 0 {
+				page = int(p[0] - '0')
+			}
+			body := pageResponseBody(page, totalPages)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+	e := NewFindingClient(client, "test-app-id")
+
+	var seen int
+	err := e.FindItemsByKeywordsAll(context.Background(), GLOBAL_ID_EBAY_US, "widget", 10, false, 2, nil, func(item Item) error {
+		seen++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen != 2 {
+		t.Fatalf("expected 2 items (maxPages=2), got %d", seen)
+	}
+}
@@ -0,0 +1,169 @@
+package ebay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// defaultEntriesPerPage is used by Source implementations when a Query
+// doesn't specify EntriesPerPage.
+const defaultEntriesPerPage = 25
+
+// ErrSourceAlreadyRegistered is returned by Manager.Register when a Source
+// with the same name has already been registered.
+var ErrSourceAlreadyRegistered = errors.New("ebay: source already registered")
+
+// ErrSourceNotFound is returned when no registered Source matches a lookup.
+var ErrSourceNotFound = errors.New("ebay: no source registered for that name or host")
+
+// ErrURLRetrievalNotSupported is returned by a Source's Search when given a
+// Query that only carries a URL (no Keywords) and the Source has no way to
+// resolve a specific listing from it.
+var ErrURLRetrievalNotSupported = errors.New("ebay: source does not support retrieving a listing by URL")
+
+// Query describes a marketplace search independent of any one Source.
+type Query struct {
+	Keywords       string
+	CategoryID     string
+	EntriesPerPage int
+	// URL is the raw listing or search URL being resolved, set when the
+	// query originates from Manager.Retrieve.
+	URL string
+}
+
+// Source is a marketplace that can be searched for live or sold items. EBay
+// is the built-in implementation; other marketplaces can be registered
+// alongside it through a Manager.
+type Source interface {
+	Name() string
+	Search(ctx context.Context, query Query) ([]Item, error)
+	Sold(ctx context.Context, query Query) ([]Item, error)
+}
+
+// Name identifies this client as the "ebay" Source.
+func (e *EBay) Name() string {
+	return "ebay"
+}
+
+// Search implements Source by delegating to FindItemsByKeywords. The Finding
+// API has no operation to resolve a specific listing from its URL (that
+// requires the Shopping API), so a Query that carries a URL but no Keywords
+// returns ErrURLRetrievalNotSupported rather than silently searching on an
+// empty keyword string.
+func (e *EBay) Search(ctx context.Context, query Query) ([]Item, error) {
+	if query.Keywords == "" && query.URL != "" {
+		return nil, fmt.Errorf("%w: %s", ErrURLRetrievalNotSupported, query.URL)
+	}
+	entriesPerPage := query.EntriesPerPage
+	if entriesPerPage == 0 {
+		entriesPerPage = defaultEntriesPerPage
+	}
+	response, err := e.FindItemsByKeywords(ctx, GLOBAL_ID_EBAY_US, query.Keywords, entriesPerPage, false)
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// Sold implements Source by delegating to FindSoldItems.
+func (e *EBay) Sold(ctx context.Context, query Query) ([]Item, error) {
+	entriesPerPage := query.EntriesPerPage
+	if entriesPerPage == 0 {
+		entriesPerPage = defaultEntriesPerPage
+	}
+	response, err := e.FindSoldItems(ctx, GLOBAL_ID_EBAY_US, query.Keywords, entriesPerPage)
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// Manager composes multiple Sources behind a single lookup surface, so
+// callers can search eBay alongside other marketplaces without caring which
+// one backs a given host or result.
+type Manager struct {
+	mu      sync.RWMutex
+	sources map[string]Source
+	hosts   map[string]Source
+}
+
+// NewManager returns an empty Manager.
+func NewManager() *Manager {
+	return &Manager{
+		sources: make(map[string]Source),
+		hosts:   make(map[string]Source),
+	}
+}
+
+// Register adds src under src.Name(), and additionally indexes it under each
+// of hosts so Retrieve can route URLs to it. It returns
+// ErrSourceAlreadyRegistered if a Source with the same name is already registered.
+func (m *Manager) Register(src Source, hosts ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.sources[src.Name()]; exists {
+		return fmt.Errorf("%w: %s", ErrSourceAlreadyRegistered, src.Name())
+	}
+	m.sources[src.Name()] = src
+	for _, host := range hosts {
+		m.hosts[host] = src
+	}
+	return nil
+}
+
+// Retrieve routes rawURL to the Source registered for its host and searches
+// it with a Query carrying that URL. The Source returns
+// ErrURLRetrievalNotSupported if it has no way to resolve a specific listing
+// from a URL alone.
+func (m *Manager) Retrieve(ctx context.Context, rawURL string) ([]Item, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	src, ok := m.hosts[parsed.Host]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: host %q", ErrSourceNotFound, parsed.Host)
+	}
+	return src.Search(ctx, Query{URL: rawURL})
+}
+
+// SearchAll fans query out to every registered Source concurrently,
+// aggregating their items and returning any per-source errors keyed by
+// source name. A failure in one Source does not prevent others from
+// contributing their results.
+func (m *Manager) SearchAll(ctx context.Context, query Query) ([]Item, map[string]error) {
+	m.mu.RLock()
+	sources := make([]Source, 0, len(m.sources))
+	for _, src := range m.sources {
+		sources = append(sources, src)
+	}
+	m.mu.RUnlock()
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		items []Item
+		errs  = make(map[string]error)
+	)
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src Source) {
+			defer wg.Done()
+			found, err := src.Search(ctx, query)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[src.Name()] = err
+				return
+			}
+			items = append(items, found...)
+		}(src)
+	}
+	wg.Wait()
+	return items, errs
+}
@@ -0,0 +1,67 @@
+package ebay
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubSource struct {
+	name  string
+	items []Item
+	err   error
+}
+
+func (s *stubSource) Name() string { return s.name }
+
+func (s *stubSource) Search(ctx context.Context, query Query) ([]Item, error) {
+	return s.items, s.err
+}
+
+func (s *stubSource) Sold(ctx context.Context, query Query) ([]Item, error) {
+	return s.items, s.err
+}
+
+func TestManagerRegisterDuplicateName(t *testing.T) {
+	m := NewManager()
+	if err := m.Register(&stubSource{name: "ebay"}); err != nil {
+		t.Fatalf("unexpected error on first register: %v", err)
+	}
+	err := m.Register(&stubSource{name: "ebay"})
+	if !errors.Is(err, ErrSourceAlreadyRegistered) {
+		t.Errorf("expected ErrSourceAlreadyRegistered, got %v", err)
+	}
+}
+
+func TestManagerSearchAllAggregatesAndReportsErrors(t *testing.T) {
+	m := NewManager()
+	_ = m.Register(&stubSource{name: "good", items: []Item{{Title: "widget"}}})
+	_ = m.Register(&stubSource{name: "bad", err: errors.New("boom")})
+
+	items, errs := m.SearchAll(context.Background(), Query{Keywords: "widget"})
+	if len(items) != 1 {
+		t.Errorf("expected 1 item, got %d", len(items))
+	}
+	if errs["bad"] == nil {
+		t.Errorf("expected an error for source %q", "bad")
+	}
+	if errs["good"] != nil {
+		t.Errorf("unexpected error for source %q: %v", "good", errs["good"])
+	}
+}
+
+func TestManagerRetrieveUnknownHost(t *testing.T) {
+	m := NewManager()
+	_, err := m.Retrieve(context.Background(), "https://unknown.example.com/item/1")
+	if !errors.Is(err, ErrSourceNotFound) {
+		t.Errorf("expected ErrSourceNotFound, got %v", err)
+	}
+}
+
+func TestEBaySearchRejectsURLOnlyQuery(t *testing.T) {
+	e := NewFindingClient(nil, "test-app-id")
+	_, err := e.Search(context.Background(), Query{URL: "https://www.ebay.com/itm/123456789012"})
+	if !errors.Is(err, ErrURLRetrievalNotSupported) {
+		t.Errorf("expected ErrURLRetrievalNotSupported, got %v", err)
+	}
+}